@@ -0,0 +1,202 @@
+// Package devserver implements `nexus serve`: an HTTP server that
+// renders a module's template and, with --watch, live-reloads
+// connected browsers over Server-Sent Events whenever the source file
+// changes on disk.
+package devserver
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+
+	"nexus/internal/parser"
+	"nexus/internal/template"
+)
+
+// reloadScript is injected into every rendered page when watching is
+// enabled. It listens for a "reload" SSE event and refreshes the page.
+const reloadScript = `<script>
+(function() {
+	var source = new EventSource("/__nexus_reload");
+	source.addEventListener("reload", function() { location.reload(); });
+})();
+</script>`
+
+// Server serves the rendered template for a single .nx module and,
+// when Watch is set, live-reloads it as the source file changes.
+type Server struct {
+	Filename string
+	Addr     string
+	Watch    bool
+
+	mu        sync.Mutex
+	reloaders []chan struct{}
+}
+
+// New creates a Server for filename, bound to addr, watching the
+// source file for changes when watch is true.
+func New(filename, addr string, watch bool) *Server {
+	return &Server{Filename: filename, Addr: addr, Watch: watch}
+}
+
+// Run starts the HTTP server and blocks until it exits or fails.
+func (s *Server) Run() error {
+	if s.Watch {
+		if err := s.watchFile(); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", s.Filename, err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleIndex)
+	if s.Watch {
+		mux.HandleFunc("/__nexus_reload", s.handleReload)
+	}
+
+	log.Printf("nexus serve: listening on %s (file: %s, watch: %v)", s.Addr, s.Filename, s.Watch)
+	return http.ListenAndServe(s.Addr, mux)
+}
+
+// handleIndex parses and renders the module's template on every
+// request, so edits made while the server is running are always
+// reflected. Parse failures are surfaced in the response body with the
+// same source-context formatting the CLI uses, so editing mistakes are
+// visible without tabbing back to the terminal.
+func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
+	output, err := s.render()
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "<pre>%s</pre>", html.EscapeString(renderedError(err)))
+		if s.Watch {
+			fmt.Fprint(w, reloadScript)
+		}
+		return
+	}
+
+	fmt.Fprint(w, output)
+	if s.Watch {
+		fmt.Fprint(w, reloadScript)
+	}
+}
+
+// render reparses the source file and renders its template against its
+// initial state values.
+func (s *Server) render() (string, error) {
+	module, err := parser.ParseFile(s.Filename)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.Parse(module.Template)
+	if err != nil {
+		return "", err
+	}
+
+	return tmpl.Render(template.NewContext(module, nil))
+}
+
+// renderedError formats a parse error the same way the CLI's
+// printError does, via parser.RenderError, for display in the browser.
+func renderedError(err error) string {
+	var b strings.Builder
+	parser.RenderError(err, &b)
+	return b.String()
+}
+
+// handleReload is a Server-Sent Events endpoint: a "reload" event is
+// pushed to this connection each time the watched file changes.
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan struct{}, 1)
+	s.mu.Lock()
+	s.reloaders = append(s.reloaders, ch)
+	s.mu.Unlock()
+	defer s.removeReloader(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	for {
+		select {
+		case <-ch:
+			fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// removeReloader drops ch from s.reloaders once its SSE connection has
+// closed, so a browser repeatedly reconnecting (as happens on every
+// live-reload) doesn't leak a channel per request.
+func (s *Server) removeReloader(ch chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, c := range s.reloaders {
+		if c == ch {
+			s.reloaders = append(s.reloaders[:i], s.reloaders[i+1:]...)
+			return
+		}
+	}
+}
+
+// watchFile starts an fsnotify watcher on the source file and notifies
+// every connected /__nexus_reload client whenever it's written.
+func (s *Server) watchFile() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := watcher.Add(s.Filename); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					s.notifyReload()
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// notifyReload wakes every connected SSE client.
+func (s *Server) notifyReload() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, ch := range s.reloaders {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrorList collects every ParseError encountered while parsing a single
+// file. Unlike returning on the first failure, this lets callers (editor
+// integrations, the CLI) surface every diagnostic from one pass, similar
+// to how golang.org/x/mod/modfile reports malformed go.mod files.
+type ErrorList struct {
+	Filename string
+	Errors   []*ParseError
+}
+
+// Error implements the error interface, joining every entry with
+// file:line:col context on its own line.
+func (el *ErrorList) Error() string {
+	var b strings.Builder
+	for i, e := range el.Errors {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		filename := e.Filename
+		if filename == "" {
+			filename = el.Filename
+		}
+		fmt.Fprintf(&b, "%s:%d:%d: %s", filename, e.Line, e.Column, e.Message)
+	}
+	return b.String()
+}
+
+// Add appends a ParseError to the list, filling in its Filename from
+// the list's own if the error doesn't already carry one.
+func (el *ErrorList) Add(err *ParseError) {
+	if err.Filename == "" {
+		err.Filename = el.Filename
+	}
+	el.Errors = append(el.Errors, err)
+}
+
+// Len reports how many errors have been recorded.
+func (el *ErrorList) Len() int {
+	return len(el.Errors)
+}
+
+// Err returns el as an error if it holds at least one entry, or nil
+// otherwise. This lets callers write `return module, errs.Err()` without
+// an extra length check at every call site.
+func (el *ErrorList) Err() error {
+	if el.Len() == 0 {
+		return nil
+	}
+	return el
+}
+
+// RenderError writes a Hugo-style source-context snippet for err to w:
+// every entry of an *ErrorList, or a lone *ParseError, found by walking
+// err and its wrapped causes. Errors that are neither just print via
+// their default Error() string.
+func RenderError(err error, w io.Writer) {
+	var list *ErrorList
+	if errors.As(err, &list) {
+		for i, pe := range list.Errors {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			pe.FormatWithSource(w)
+		}
+		return
+	}
+
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		pe.FormatWithSource(w)
+		return
+	}
+
+	fmt.Fprintln(w, err)
+}
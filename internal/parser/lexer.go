@@ -0,0 +1,291 @@
+package parser
+
+import (
+	"fmt"
+)
+
+// TokenType identifies the lexical category of a Token.
+type TokenType int
+
+const (
+	TokEOF TokenType = iota
+	TokIdent
+	TokKeyword
+	TokLBrace
+	TokRBrace
+	TokLParen
+	TokRParen
+	TokColon
+	TokEquals
+	TokArrow
+	TokComma
+	TokString
+	TokNumber
+	TokNewline
+	TokComment
+)
+
+func (t TokenType) String() string {
+	switch t {
+	case TokEOF:
+		return "EOF"
+	case TokIdent:
+		return "identifier"
+	case TokKeyword:
+		return "keyword"
+	case TokLBrace:
+		return "'{'"
+	case TokRBrace:
+		return "'}'"
+	case TokLParen:
+		return "'('"
+	case TokRParen:
+		return "')'"
+	case TokColon:
+		return "':'"
+	case TokEquals:
+		return "'='"
+	case TokArrow:
+		return "'->'"
+	case TokComma:
+		return "','"
+	case TokString:
+		return "string"
+	case TokNumber:
+		return "number"
+	case TokNewline:
+		return "newline"
+	case TokComment:
+		return "comment"
+	default:
+		return "unknown"
+	}
+}
+
+// Token is a single lexical unit with its exact source position, so
+// ParseError can report a precise line and column rather than just
+// pointing at the start of the enclosing line.
+type Token struct {
+	Type   TokenType
+	Value  string
+	Line   int
+	Column int
+}
+
+// lexError carries the source position a lexical error occurred at, so
+// the parser can build a ParseError pointing at the real offending
+// character instead of wherever the lexer happens to be by the time the
+// error is observed.
+type lexError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *lexError) Error() string { return e.Message }
+
+// keywords are the identifiers that lex as TokKeyword instead of
+// TokIdent.
+var keywords = map[string]bool{
+	KeywordModule:   true,
+	KeywordState:    true,
+	KeywordAction:   true,
+	KeywordView:     true,
+	KeywordTemplate: true,
+}
+
+// Lexer turns Nexus source into a stream of Tokens one at a time,
+// tracking line and column as it goes.
+type Lexer struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+// NewLexer creates a Lexer over src, starting at line 1, column 1.
+func NewLexer(src string) *Lexer {
+	return &Lexer{src: src, line: 1, column: 1}
+}
+
+// Next returns the next token in the stream, or a TokEOF token once the
+// source is exhausted.
+func (l *Lexer) Next() (Token, error) {
+	for l.pos < len(l.src) {
+		b := l.src[l.pos]
+
+		switch {
+		case b == '\n':
+			line, col := l.line, l.column
+			l.advance()
+			return Token{Type: TokNewline, Value: "\n", Line: line, Column: col}, nil
+
+		case b == ' ' || b == '\t' || b == '\r':
+			l.advance()
+
+		case b == '/' && l.peekAt(1) == '/':
+			return l.lexComment(), nil
+
+		case b == '{':
+			return l.single(TokLBrace, "{"), nil
+		case b == '}':
+			return l.single(TokRBrace, "}"), nil
+		case b == '(':
+			return l.single(TokLParen, "("), nil
+		case b == ')':
+			return l.single(TokRParen, ")"), nil
+		case b == ':':
+			return l.single(TokColon, ":"), nil
+		case b == ',':
+			return l.single(TokComma, ","), nil
+		case b == '=':
+			return l.single(TokEquals, "="), nil
+
+		case b == '-' && l.peekAt(1) == '>':
+			line, col := l.line, l.column
+			l.advance()
+			l.advance()
+			return Token{Type: TokArrow, Value: "->", Line: line, Column: col}, nil
+
+		case b == '"' || b == '\'':
+			return l.lexString(b)
+
+		case isDigit(b):
+			return l.lexNumber(), nil
+
+		case isIdentStart(b):
+			return l.lexIdentOrKeyword(), nil
+
+		default:
+			line, col := l.line, l.column
+			l.advance()
+			return Token{}, &lexError{Line: line, Column: col, Message: fmt.Sprintf("unexpected character %q", b)}
+		}
+	}
+
+	return Token{Type: TokEOF, Line: l.line, Column: l.column}, nil
+}
+
+// RawBlock consumes source text up to (and including) the '}' that
+// matches an already-consumed opening '{', returning everything between
+// them verbatim. Used for action and template bodies, whose contents
+// aren't Nexus tokens (the template body in particular holds its own
+// `{{ }}`/`{% %}` syntax) and must be captured as-is rather than
+// re-lexed, replacing the line-by-line brace counting the parser used
+// to do for this on its own.
+func (l *Lexer) RawBlock() (string, error) {
+	start := l.pos
+	depth := 1
+
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				content := l.src[start:l.pos]
+				l.advance() // consume the matching '}'
+				return content, nil
+			}
+		}
+		l.advance()
+	}
+
+	return "", &lexError{Line: l.line, Column: l.column, Message: "unclosed block"}
+}
+
+// Position reports the lexer's current line and column, useful for
+// error reporting around RawBlock calls.
+func (l *Lexer) Position() (line, column int) {
+	return l.line, l.column
+}
+
+func (l *Lexer) peekAt(offset int) byte {
+	if l.pos+offset >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos+offset]
+}
+
+// advance consumes one byte, updating line/column bookkeeping.
+func (l *Lexer) advance() byte {
+	b := l.src[l.pos]
+	l.pos++
+	if b == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+	return b
+}
+
+func (l *Lexer) single(typ TokenType, value string) Token {
+	line, col := l.line, l.column
+	l.advance()
+	return Token{Type: typ, Value: value, Line: line, Column: col}
+}
+
+func (l *Lexer) lexComment() Token {
+	line, col := l.line, l.column
+	start := l.pos
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		l.advance()
+	}
+	return Token{Type: TokComment, Value: l.src[start:l.pos], Line: line, Column: col}
+}
+
+func (l *Lexer) lexString(quote byte) (Token, error) {
+	line, col := l.line, l.column
+	l.advance() // opening quote
+
+	start := l.pos
+	for l.pos < len(l.src) && l.src[l.pos] != quote {
+		l.advance()
+	}
+	if l.pos >= len(l.src) {
+		return Token{}, &lexError{Line: line, Column: col, Message: "unterminated string literal"}
+	}
+
+	value := l.src[start:l.pos]
+	l.advance() // closing quote
+
+	return Token{Type: TokString, Value: value, Line: line, Column: col}, nil
+}
+
+func (l *Lexer) lexNumber() Token {
+	line, col := l.line, l.column
+	start := l.pos
+	for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+		l.advance()
+	}
+	return Token{Type: TokNumber, Value: l.src[start:l.pos], Line: line, Column: col}
+}
+
+func (l *Lexer) lexIdentOrKeyword() Token {
+	line, col := l.line, l.column
+	start := l.pos
+	for l.pos < len(l.src) && isIdentPart(l.src[l.pos]) {
+		l.advance()
+	}
+
+	value := l.src[start:l.pos]
+	typ := TokIdent
+	if keywords[value] {
+		typ = TokKeyword
+	}
+	return Token{Type: typ, Value: value, Line: line, Column: col}
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isIdentStart(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isIdentPart(b byte) bool {
+	return isIdentStart(b) || isDigit(b)
+}
@@ -26,12 +26,15 @@ type View struct {
 type Action struct {
 	Name       string      `json:"name"`
 	Parameters []Parameter `json:"parameters"`
+	ReturnType string      `json:"returnType,omitempty"`
+	Body       []string    `json:"body,omitempty"`
 }
 
 // Parameter represents a parameter for an action
 type Parameter struct {
-	Name string `json:"name"`
-	Type string `json:"type"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Default string `json:"default,omitempty"`
 }
 
 // StateProperty is an alias for Property for backward compatibility
@@ -1,13 +1,11 @@
 package parser
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
-	"strconv"
 	"strings"
-	"unicode"
 )
 
 // Constants for parsing
@@ -34,10 +32,11 @@ var (
 
 // ParseError represents a parsing error with context
 type ParseError struct {
-	Line    int
-	Column  int
-	Message string
-	Context string
+	Filename string
+	Line     int
+	Column   int
+	Message  string
+	Context  string
 }
 
 func (e *ParseError) Error() string {
@@ -45,23 +44,67 @@ func (e *ParseError) Error() string {
 		e.Line, e.Column, e.Message, e.Context)
 }
 
-// Parser holds the parsing state
+// FormatWithSource writes the error's message followed by the offending
+// source line, the two lines above and below it for context, and a
+// caret-and-tilde marker under the exact column, similar to how Hugo
+// surfaces config/content errors in its dev server.
+func (e *ParseError) FormatWithSource(w io.Writer) error {
+	fmt.Fprintf(w, "%s:%d:%d: %s\n", e.Filename, e.Line, e.Column, e.Message)
+
+	data, err := os.ReadFile(e.Filename)
+	if err != nil {
+		return nil // best-effort: the message above was still printed
+	}
+	lines := strings.Split(string(data), "\n")
+
+	start := e.Line - 3
+	if start < 0 {
+		start = 0
+	}
+	end := e.Line + 2
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	width := len(fmt.Sprintf("%d", end))
+	for i := start; i < end; i++ {
+		lineNo := i + 1
+		fmt.Fprintf(w, "  %*d | %s\n", width, lineNo, lines[i])
+
+		if lineNo == e.Line {
+			column := e.Column
+			if column < 1 {
+				column = 1
+			}
+			fmt.Fprintf(w, "  %s | %s^~~~\n", strings.Repeat(" ", width), strings.Repeat(" ", column-1))
+		}
+	}
+
+	return nil
+}
+
+// knownParamTypes are the primitive types usable in an action parameter
+// or return annotation without matching an existing state property.
+var knownParamTypes = map[string]bool{
+	"string": true,
+	"int":    true,
+	"float":  true,
+	"bool":   true,
+}
+
+// Parser holds the parsing state. It consumes a token stream from a
+// Lexer via recursive-descent methods, one per grammar production,
+// rather than inspecting raw source lines directly.
 type Parser struct {
 	filename string
-	lines    []string
-	current  int
-	line     int
-	column   int
+	lexer    *Lexer
+	tok      Token
+	errs     ErrorList
 }
 
 // NewParser creates a new parser instance
 func NewParser(filename string) *Parser {
-	return &Parser{
-		filename: filename,
-		current:  0,
-		line:     1,
-		column:   1,
-	}
+	return &Parser{filename: filename}
 }
 
 // ParseFile parses a Nexus file and returns a Module
@@ -71,25 +114,47 @@ func ParseFile(filename string) (*Module, error) {
 }
 
 // parse is the main parsing entry point
-func (p *Parser) parse() (*Module, error) {
+func (p *Parser) parse() (module *Module, err error) {
+	p.errs.Filename = p.filename
+
 	if err := p.validateFile(); err != nil {
 		return nil, err
 	}
 
-	if err := p.loadFile(); err != nil {
+	src, err := p.loadFile()
+	if err != nil {
 		return nil, fmt.Errorf("failed to load file %s: %w", p.filename, err)
 	}
 
-	if len(p.lines) == 0 {
-		return nil, p.newError("file is empty", "")
+	if strings.TrimSpace(src) == "" {
+		return nil, &ParseError{Filename: p.filename, Line: 1, Column: 1, Message: "file is empty"}
+	}
+
+	p.lexer = NewLexer(src)
+	if err := p.advance(); err != nil {
+		return nil, err
 	}
 
-	module, err := p.parseModule()
+	// A malformed file should never crash the tool: recover any internal
+	// panic (e.g. a slicing bug tripped by unusual input) as a parse
+	// error so callers always get an error value, never a stack trace.
+	defer func() {
+		if r := recover(); r != nil {
+			p.errs.Add(p.newError(fmt.Sprintf("internal parser error: %v", r), ""))
+			module, err = nil, p.errs.Err()
+		}
+	}()
+
+	mod, err := p.parseModule()
 	if err != nil {
 		return nil, err
 	}
 
-	return module, nil
+	if listErr := p.errs.Err(); listErr != nil {
+		return nil, listErr
+	}
+
+	return mod, nil
 }
 
 // validateFile checks if the file has the correct extension and exists
@@ -106,25 +171,59 @@ func (p *Parser) validateFile() error {
 	return nil
 }
 
-// loadFile reads the file content into lines
-func (p *Parser) loadFile() error {
-	file, err := os.Open(p.filename)
+// loadFile reads the full file content for the lexer to consume.
+func (p *Parser) loadFile() (string, error) {
+	data, err := os.ReadFile(p.filename)
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer file.Close()
+	return string(data), nil
+}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		p.lines = append(p.lines, scanner.Text())
+// advance fetches the next significant token (skipping newlines and
+// comments, which carry no grammatical meaning) into p.tok.
+func (p *Parser) advance() error {
+	for {
+		tok, err := p.lexer.Next()
+		if err != nil {
+			return p.lexParseError(err)
+		}
+		if tok.Type == TokNewline || tok.Type == TokComment {
+			continue
+		}
+		p.tok = tok
+		return nil
 	}
+}
 
-	return scanner.Err()
+// lexParseError converts a lexer error into a ParseError at the
+// position the lexer reported, falling back to the current token's
+// position if the error didn't carry one of its own.
+func (p *Parser) lexParseError(err error) *ParseError {
+	if le, ok := err.(*lexError); ok {
+		return &ParseError{Filename: p.filename, Line: le.Line, Column: le.Column, Message: le.Message}
+	}
+	return p.newError(err.Error(), "")
+}
+
+// rawBlock reads a brace-delimited body verbatim via the lexer, for
+// action and template bodies whose contents aren't meant to be
+// re-tokenized as Nexus grammar. p.tok must currently be the opening
+// TokLBrace (already consumed from the lexer's position, but not yet
+// advanced past in the parser's token stream).
+func (p *Parser) rawBlock() (string, error) {
+	raw, err := p.lexer.RawBlock()
+	if err != nil {
+		return "", p.lexParseError(err)
+	}
+	if err := p.advance(); err != nil {
+		return "", err
+	}
+	return raw, nil
 }
 
 // parseModule parses the entire module with block syntax
 func (p *Parser) parseModule() (*Module, error) {
-	// Parse module declaration with opening brace
 	moduleName, err := p.parseModuleDeclaration()
 	if err != nil {
 		return nil, err
@@ -138,254 +237,450 @@ func (p *Parser) parseModule() (*Module, error) {
 		Template: []string{},
 	}
 
-	// Parse module body until closing brace
-	blockDepth := 1
-	for p.hasMoreLines() && blockDepth > 0 {
-		line := strings.TrimSpace(p.currentLine())
+	for p.tok.Type != TokRBrace && p.tok.Type != TokEOF {
+		p.parseModuleElementRecover(module)
+	}
 
-		// Check for closing brace
-		if line == BlockEnd {
-			blockDepth--
-			if blockDepth == 0 {
-				break
-			}
+	if p.tok.Type != TokRBrace {
+		p.errs.Add(p.newError("unclosed module block", ""))
+	}
+
+	return module, nil
+}
+
+// parseModuleElementRecover wraps parseModuleElement so a single bad
+// declaration (a recoverable ParseError, or even an internal panic)
+// doesn't abort parsing of the rest of the file. The error is recorded
+// on p.errs and the parser resyncs to the next element it recognizes.
+func (p *Parser) parseModuleElementRecover(module *Module) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.errs.Add(p.newError(fmt.Sprintf("internal parser error: %v", r), ""))
+			p.resync()
 		}
+	}()
 
-		if err := p.parseModuleElement(module); err != nil {
-			return nil, err
+	if err := p.parseModuleElement(module); err != nil {
+		pe, ok := err.(*ParseError)
+		if !ok {
+			pe = p.newError(err.Error(), "")
 		}
+		p.errs.Add(pe)
+		p.resync()
 	}
+}
 
-	if blockDepth > 0 {
-		return nil, p.newError("unclosed module block", "")
+// resync skips forward to the next point parsing can safely resume
+// from after a recoverable error: a top-level keyword, the module's
+// closing brace, or end of file. It tracks brace depth so a body the
+// failed declaration never got to consume (e.g. an action's `{ ... }`
+// when the error was raised while parsing its signature) is skipped as
+// a balanced unit instead of having its closing '}' mistaken for the
+// module's own closing brace.
+func (p *Parser) resync() {
+	if p.tok.Type == TokEOF {
+		return
+	}
+	if err := p.advance(); err != nil {
+		return
+	}
+
+	depth := 0
+	for p.tok.Type != TokEOF {
+		switch p.tok.Type {
+		case TokLBrace:
+			depth++
+		case TokRBrace:
+			if depth == 0 {
+				return
+			}
+			depth--
+		case TokKeyword:
+			if depth == 0 {
+				switch p.tok.Value {
+				case KeywordState, KeywordAction, KeywordView, KeywordTemplate:
+					return
+				}
+			}
+		}
+		if err := p.advance(); err != nil {
+			return
+		}
 	}
-
-	return module, nil
 }
 
 // parseModuleDeclaration parses the module declaration with opening brace
 func (p *Parser) parseModuleDeclaration() (string, error) {
-	if !p.hasMoreLines() {
-		return "", p.newError("expected module declaration", "")
+	if p.tok.Type != TokKeyword || p.tok.Value != KeywordModule {
+		return "", p.newError("expected module declaration: 'module Name {'", p.describeTok())
+	}
+	if err := p.advance(); err != nil {
+		return "", err
 	}
 
-	line := p.currentLine()
-	trimmed := strings.TrimSpace(line)
-
-	// Handle "module Name {" syntax
-	if strings.HasPrefix(trimmed, KeywordModule+" ") && strings.HasSuffix(trimmed, " "+BlockStart) {
-		// Extract module name between "module " and " {"
-		content := strings.TrimSpace(trimmed[len(KeywordModule):])
-		moduleName := strings.TrimSpace(strings.TrimSuffix(content, BlockStart))
-
-		if moduleName == "" {
-			return "", p.newError("empty module name", trimmed)
-		}
+	if p.tok.Type != TokIdent {
+		return "", p.newError("expected module name after 'module'", p.describeTok())
+	}
+	moduleName := p.tok.Value
+	nameLine, nameCol := p.tok.Line, p.tok.Column
+	if err := p.advance(); err != nil {
+		return "", err
+	}
 
-		if !p.isValidModuleName(moduleName) {
-			return "", p.newError("invalid module name: must start with letter and contain only letters, numbers, and underscores", moduleName)
+	if !p.isValidModuleName(moduleName) {
+		return "", &ParseError{
+			Filename: p.filename,
+			Line:     nameLine, Column: nameCol,
+			Message: "invalid module name: must start with letter and contain only letters, numbers, and underscores",
+			Context: moduleName,
 		}
+	}
 
-		p.advance()
-		return moduleName, nil
+	if p.tok.Type != TokLBrace {
+		return "", p.newError("expected '{' after module name", p.describeTok())
+	}
+	if err := p.advance(); err != nil {
+		return "", err
 	}
 
-	return "", p.newError("expected module declaration with opening brace: 'module Name {'", trimmed)
+	return moduleName, nil
 }
 
 // parseModuleElement parses individual module elements (state, action, view, template)
 func (p *Parser) parseModuleElement(module *Module) error {
-	line := strings.TrimSpace(p.currentLine())
-
-	// Skip empty lines and comments
-	if line == "" || strings.HasPrefix(line, "//") {
-		p.advance()
-		return nil
+	if p.tok.Type != TokKeyword {
+		return p.newError("unexpected token - expected 'state', 'action', 'view', or 'template'", p.describeTok())
 	}
 
-	// Skip closing braces (handled by parent)
-	if line == BlockEnd {
-		p.advance()
-		return nil
-	}
-
-	switch {
-	case strings.HasPrefix(line, KeywordState+" "):
+	switch p.tok.Value {
+	case KeywordState:
 		return p.parseState(module)
-	case strings.HasPrefix(line, KeywordAction+" "):
+	case KeywordAction:
 		return p.parseAction(module)
-	case strings.HasPrefix(line, KeywordView+" "):
+	case KeywordView:
 		return p.parseView(module)
-	case strings.HasPrefix(line, KeywordTemplate+" "+BlockStart):
+	case KeywordTemplate:
 		return p.parseTemplate(module)
 	default:
-		// Better error message with more context
-		return p.newError(fmt.Sprintf("unexpected token - expected 'state', 'action', 'view', or 'template', got: '%s'", line), line)
+		return p.newError("unexpected token - expected 'state', 'action', 'view', or 'template'", p.describeTok())
 	}
 }
 
-// parseState parses a state declaration
+// parseState parses a state declaration: `state name: type = value`
 func (p *Parser) parseState(module *Module) error {
-	line := strings.TrimSpace(p.currentLine())
-	stateLine := strings.TrimSpace(strings.TrimPrefix(line, KeywordState+" "))
-
-	// Parse: name: type = value
-	colonIndex := strings.Index(stateLine, ":")
-	if colonIndex == -1 {
-		return p.newError("invalid state declaration: missing colon", line)
+	if err := p.advance(); err != nil {
+		return err
 	}
 
-	name := strings.TrimSpace(stateLine[:colonIndex])
+	if p.tok.Type != TokIdent {
+		return p.newError("invalid state variable name", p.describeTok())
+	}
+	name := p.tok.Value
 	if !p.isValidIdentifier(name) {
 		return p.newError("invalid state variable name", name)
 	}
+	if err := p.advance(); err != nil {
+		return err
+	}
 
-	remainder := strings.TrimSpace(stateLine[colonIndex+1:])
-
-	// Parse type and optional value
-	var typ, value string
-	if equalIndex := strings.Index(remainder, "="); equalIndex != -1 {
-		typ = strings.TrimSpace(remainder[:equalIndex])
-		value = strings.TrimSpace(remainder[equalIndex+1:])
-		value = p.parseStringValue(value)
-	} else {
-		typ = remainder
+	if p.tok.Type != TokColon {
+		return p.newError("invalid state declaration: missing colon", p.describeTok())
+	}
+	if err := p.advance(); err != nil {
+		return err
 	}
 
-	if typ == "" {
-		return p.newError("missing type in state declaration", line)
+	if p.tok.Type != TokIdent {
+		return p.newError("missing type in state declaration", p.describeTok())
+	}
+	typ := p.tok.Value
+	if err := p.advance(); err != nil {
+		return err
 	}
 
-	module.State = append(module.State, Property{
-		Name:  name,
-		Type:  typ,
-		Value: value,
-	})
+	value := ""
+	if p.tok.Type == TokEquals {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		v, err := p.parseValueLiteral()
+		if err != nil {
+			return err
+		}
+		value = v
+	}
 
-	p.advance()
+	module.State = append(module.State, Property{Name: name, Type: typ, Value: value})
 	return nil
 }
 
-// parseAction parses an action declaration
-func (p *Parser) parseAction(module *Module) error {
-	line := strings.TrimSpace(p.currentLine())
-	actionLine := strings.TrimSpace(strings.TrimPrefix(line, KeywordAction+" "))
-
-	// Parse action name and optional parameters
-	parts := strings.Fields(actionLine)
-	if len(parts) == 0 {
-		return p.newError("missing action name", line)
+// parseValueLiteral consumes a string, number, or bare identifier
+// (e.g. true/false) as a literal value, returning its textual form.
+func (p *Parser) parseValueLiteral() (string, error) {
+	switch p.tok.Type {
+	case TokString, TokNumber, TokIdent:
+		value := p.tok.Value
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		return value, nil
+	default:
+		return "", p.newError("expected a value after '='", p.describeTok())
 	}
+}
 
-	actionName := parts[0]
+// parseAction parses an action declaration. Two forms are supported:
+// the legacy bare "action name" with no parameters or body, and
+// "action name(params) -> ReturnType { body }" with a parenthesized,
+// comma-separated parameter list and an optional return annotation.
+func (p *Parser) parseAction(module *Module) error {
+	if err := p.advance(); err != nil {
+		return err
+	}
 
+	if p.tok.Type != TokIdent {
+		return p.newError("missing action name", p.describeTok())
+	}
+	actionName := p.tok.Value
 	if !p.isValidIdentifier(actionName) {
 		return p.newError("invalid action name", actionName)
 	}
+	if err := p.advance(); err != nil {
+		return err
+	}
 
-	// TODO: Parse parameters if needed
-	// For now, we'll just store the action name
-	module.Actions = append(module.Actions, Action{
-		Name:       actionName,
-		Parameters: []Parameter{},
-	})
+	if p.tok.Type != TokLParen {
+		module.Actions = append(module.Actions, Action{Name: actionName, Parameters: []Parameter{}})
+		return nil
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
 
-	p.advance()
-	return nil
-}
+	params, err := p.parseParameterList(module)
+	if err != nil {
+		return err
+	}
 
-// parseView parses a view declaration
-func (p *Parser) parseView(module *Module) error {
-	line := strings.TrimSpace(p.currentLine())
-	viewName := strings.TrimSpace(strings.TrimPrefix(line, KeywordView+" "))
+	if p.tok.Type != TokRParen {
+		return p.newError("unclosed parameter list in action declaration", p.describeTok())
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
 
-	if viewName == "" {
-		return p.newError("missing view name", line)
+	returnType := ""
+	if p.tok.Type == TokArrow {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.tok.Type != TokIdent {
+			return p.newError("expected return type after '->'", p.describeTok())
+		}
+		returnType = p.tok.Value
+		if !p.isKnownType(returnType, module) {
+			return p.newError(fmt.Sprintf("unknown return type %q", returnType), returnType)
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
 	}
 
-	if !p.isValidIdentifier(viewName) {
-		return p.newError("invalid view name", viewName)
+	if p.tok.Type != TokLBrace {
+		return p.newError("expected '{' after action signature", p.describeTok())
+	}
+	raw, err := p.rawBlock()
+	if err != nil {
+		return err
 	}
 
-	module.Views = append(module.Views, View{
-		Name:    viewName,
-		Content: []string{},
+	module.Actions = append(module.Actions, Action{
+		Name:       actionName,
+		Parameters: params,
+		ReturnType: returnType,
+		Body:       splitBodyLines(raw),
 	})
-
-	p.advance()
 	return nil
 }
 
-// parseTemplate parses a template block within a module
-func (p *Parser) parseTemplate(module *Module) error {
-	line := strings.TrimSpace(p.currentLine())
-	if !strings.HasSuffix(line, BlockStart) {
-		return p.newError("expected opening brace after template", line)
+// parseParameterList parses the comma-separated contents of an action's
+// parameter list, e.g. "username: string, count: int = 0".
+func (p *Parser) parseParameterList(module *Module) ([]Parameter, error) {
+	if p.tok.Type == TokRParen {
+		return []Parameter{}, nil
 	}
 
-	p.advance() // Skip template { line
+	var params []Parameter
+	for {
+		param, err := p.parseParameter(module)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
 
-	var templateLines []string
-	blockDepth := 1
+		if p.tok.Type != TokComma {
+			break
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
 
-	for p.hasMoreLines() && blockDepth > 0 {
-		line := p.currentLine()
+	return params, nil
+}
 
-		// Count braces to handle nested JSX elements
-		openBraces := strings.Count(line, BlockStart)
-		closeBraces := strings.Count(line, BlockEnd)
+// parseParameter parses a single "name: type" or "name: type = default"
+// parameter.
+func (p *Parser) parseParameter(module *Module) (Parameter, error) {
+	if p.tok.Type != TokIdent {
+		return Parameter{}, p.newError("expected parameter name", p.describeTok())
+	}
+	name := p.tok.Value
+	if !p.isValidIdentifier(name) {
+		return Parameter{}, p.newError("invalid parameter name", name)
+	}
+	if err := p.advance(); err != nil {
+		return Parameter{}, err
+	}
 
-		// Update block depth
-		blockDepth += openBraces - closeBraces
+	if p.tok.Type != TokColon {
+		return Parameter{}, p.newError("parameter missing type annotation", name)
+	}
+	if err := p.advance(); err != nil {
+		return Parameter{}, err
+	}
+
+	if p.tok.Type != TokIdent {
+		return Parameter{}, p.newError("expected parameter type", p.describeTok())
+	}
+	typ := p.tok.Value
+	if !p.isKnownType(typ, module) {
+		return Parameter{}, p.newError(fmt.Sprintf("unknown parameter type %q", typ), typ)
+	}
+	if err := p.advance(); err != nil {
+		return Parameter{}, err
+	}
 
-		// If we're still inside the template block, add the line
-		if blockDepth > 0 {
-			templateLines = append(templateLines, line)
+	defaultValue := ""
+	if p.tok.Type == TokEquals {
+		if err := p.advance(); err != nil {
+			return Parameter{}, err
 		}
+		v, err := p.parseValueLiteral()
+		if err != nil {
+			return Parameter{}, err
+		}
+		defaultValue = v
+	}
+
+	return Parameter{Name: name, Type: typ, Default: defaultValue}, nil
+}
 
-		p.advance()
+// isKnownType reports whether typ is one of the built-in parameter
+// types, or matches the type of an existing state property.
+func (p *Parser) isKnownType(typ string, module *Module) bool {
+	if knownParamTypes[typ] {
+		return true
+	}
+	for _, prop := range module.State {
+		if prop.Type == typ {
+			return true
+		}
 	}
+	return false
+}
 
-	if blockDepth > 0 {
-		return p.newError("unclosed template block", "")
+// parseView parses a view declaration
+func (p *Parser) parseView(module *Module) error {
+	if err := p.advance(); err != nil {
+		return err
 	}
 
-	module.Template = templateLines
+	if p.tok.Type != TokIdent {
+		return p.newError("missing view name", p.describeTok())
+	}
+	viewName := p.tok.Value
+	if !p.isValidIdentifier(viewName) {
+		return p.newError("invalid view name", viewName)
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+
+	module.Views = append(module.Views, View{Name: viewName, Content: []string{}})
 	return nil
 }
 
-// Helper methods
+// parseTemplate parses a template block within a module. Its contents
+// aren't Nexus grammar (they hold their own `{{ }}` / `{% %}` syntax),
+// so they're captured verbatim rather than tokenized.
+func (p *Parser) parseTemplate(module *Module) error {
+	if err := p.advance(); err != nil {
+		return err
+	}
 
-// currentLine returns the current line being parsed
-func (p *Parser) currentLine() string {
-	if p.current >= len(p.lines) {
-		return ""
+	if p.tok.Type != TokLBrace {
+		return p.newError("expected opening brace after template", p.describeTok())
+	}
+
+	raw, err := p.rawBlock()
+	if err != nil {
+		return err
 	}
-	return p.lines[p.current]
-}
 
-// hasMoreLines checks if there are more lines to parse
-func (p *Parser) hasMoreLines() bool {
-	return p.current < len(p.lines)
+	module.Template = splitTemplateLines(raw)
+	return nil
 }
 
-// advance moves to the next line
-func (p *Parser) advance() {
-	if p.current < len(p.lines) {
-		p.current++
-		p.line++
-		p.column = 1
+// splitBodyLines splits a raw captured action body into trimmed,
+// non-empty lines.
+func splitBodyLines(raw string) []string {
+	var lines []string
+	for _, line := range strings.Split(raw, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			lines = append(lines, trimmed)
+		}
 	}
+	return lines
+}
+
+// splitTemplateLines splits a raw captured template body into lines,
+// preserving indentation and blank lines (templates render as markup,
+// where that whitespace matters). The newline immediately after the
+// opening brace and the one immediately before the closing brace are
+// dropped, since those belong to the declaration's own line, not the
+// template content.
+func splitTemplateLines(raw string) []string {
+	lines := strings.Split(raw, "\n")
+	if len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
 }
 
-// newError creates a new ParseError with current position
+// Helper methods
+
+// newError creates a new ParseError at the current token's position.
 func (p *Parser) newError(message, context string) *ParseError {
 	return &ParseError{
-		Line:    p.line,
-		Column:  p.column,
-		Message: message,
-		Context: context,
+		Filename: p.filename,
+		Line:     p.tok.Line,
+		Column:   p.tok.Column,
+		Message:  message,
+		Context:  context,
+	}
+}
+
+// describeTok renders the current token for use as error context, e.g.
+// `unexpected token` messages.
+func (p *Parser) describeTok() string {
+	if p.tok.Value != "" {
+		return p.tok.Value
 	}
+	return p.tok.Type.String()
 }
 
 // isValidIdentifier checks if a string is a valid identifier
@@ -397,32 +692,3 @@ func (p *Parser) isValidIdentifier(name string) bool {
 func (p *Parser) isValidModuleName(name string) bool {
 	return moduleNameRegex.MatchString(name)
 }
-
-// parseStringValue parses and cleans string values
-func (p *Parser) parseStringValue(value string) string {
-	// Remove surrounding quotes if present
-	if len(value) >= 2 {
-		if (strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`)) ||
-			(strings.HasPrefix(value, `'`) && strings.HasSuffix(value, `'`)) {
-			return value[1 : len(value)-1]
-		}
-	}
-
-	// Handle numeric values
-	if _, err := strconv.Atoi(value); err == nil {
-		return value
-	}
-	if _, err := strconv.ParseFloat(value, 64); err == nil {
-		return value
-	}
-	if value == "true" || value == "false" {
-		return value
-	}
-
-	return value
-}
-
-// isWhitespace checks if a character is whitespace
-func (p *Parser) isWhitespace(r rune) bool {
-	return unicode.IsSpace(r)
-}
@@ -16,7 +16,7 @@ func TestParseFile(t *testing.T) {
 	}{
 		{
 			name:        "valid file",
-			content:     "module App\nview Home\naction login\naction logout",
+			content:     "module App {\nview Home\naction login\naction logout\n}",
 			expectErr:   false,
 			wantName:    "App",
 			wantViews:   1,
@@ -70,3 +70,136 @@ func TestParseFile(t *testing.T) {
 		})
 	}
 }
+
+func TestParseActionSignature(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		expectErr  bool
+		wantParams []Parameter
+		wantReturn string
+		wantBody   []string
+	}{
+		{
+			name:    "legacy bare action",
+			content: "module App {\naction login\n}",
+		},
+		{
+			name:       "typed params and return type",
+			content:    "module App {\naction login(username: string, password: string) -> bool {\ndoLogin()\n}\n}",
+			wantParams: []Parameter{{Name: "username", Type: "string"}, {Name: "password", Type: "string"}},
+			wantReturn: "bool",
+			wantBody:   []string{"doLogin()"},
+		},
+		{
+			name:       "default value",
+			content:    "module App {\naction retry(count: int = 0) {\n}\n}",
+			wantParams: []Parameter{{Name: "count", Type: "int", Default: "0"}},
+		},
+		{
+			name:      "unknown parameter type",
+			content:   "module App {\naction login(username: wat) {\n}\n}",
+			expectErr: true,
+		},
+		{
+			name:      "missing type annotation",
+			content:   "module App {\naction login(username) {\n}\n}",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := "temp_action_test.nx"
+			if err := os.WriteFile(filename, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to write temp file: %v", err)
+			}
+			defer os.Remove(filename)
+
+			mod, err := ParseFile(filename)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			action := mod.Actions[0]
+			if len(action.Parameters) != len(tt.wantParams) {
+				t.Fatalf("Parameters = %+v; want %+v", action.Parameters, tt.wantParams)
+			}
+			for i, param := range action.Parameters {
+				if param != tt.wantParams[i] {
+					t.Errorf("Parameter[%d] = %+v; want %+v", i, param, tt.wantParams[i])
+				}
+			}
+			if action.ReturnType != tt.wantReturn {
+				t.Errorf("ReturnType = %q; want %q", action.ReturnType, tt.wantReturn)
+			}
+			if tt.wantBody != nil {
+				if len(action.Body) != len(tt.wantBody) {
+					t.Fatalf("Body = %+v; want %+v", action.Body, tt.wantBody)
+				}
+				for i, line := range action.Body {
+					if line != tt.wantBody[i] {
+						t.Errorf("Body[%d] = %q; want %q", i, line, tt.wantBody[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestResyncPastBraceBody verifies that an error raised while parsing a
+// declaration's signature - before its brace-delimited body has been
+// consumed - doesn't cause resync to mistake that body's closing brace
+// for the module's own. Every later declaration should still parse, and
+// the module's real closing brace should still be detected, so the
+// caller sees every diagnostic from one pass instead of losing the rest
+// of the file. Drives parseModule directly (rather than ParseFile) since
+// the public entry point discards the partially built module once any
+// error is recorded, and we need to inspect what survived resync.
+func TestResyncPastBraceBody(t *testing.T) {
+	content := "module App {\n" +
+		"action foo(x: badtype) {\n" +
+		"doStuff()\n" +
+		"}\n" +
+		"state good: string = \"ok\"\n" +
+		"view Home\n" +
+		"}"
+
+	filename := "temp_resync_test.nx"
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write temp file: %v", err)
+	}
+	defer os.Remove(filename)
+
+	p := NewParser(filename)
+	p.errs.Filename = filename
+	src, err := p.loadFile()
+	if err != nil {
+		t.Fatalf("failed to load file: %v", err)
+	}
+	p.lexer = NewLexer(src)
+	if err := p.advance(); err != nil {
+		t.Fatalf("failed to prime lexer: %v", err)
+	}
+
+	mod, err := p.parseModule()
+	if err != nil {
+		t.Fatalf("unexpected fatal error: %v", err)
+	}
+	if p.errs.Len() != 1 {
+		t.Fatalf("errors = %d; want 1 (%v)", p.errs.Len(), p.errs.Errors)
+	}
+
+	if len(mod.State) != 1 || mod.State[0].Name != "good" {
+		t.Errorf("State = %+v; want one property named %q", mod.State, "good")
+	}
+	if len(mod.Views) != 1 || mod.Views[0].Name != "Home" {
+		t.Errorf("Views = %+v; want one view named %q", mod.Views, "Home")
+	}
+}
@@ -0,0 +1,159 @@
+package template
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// evalExpr evaluates a `{{ expr }}` or `{% if expr %}` body: a
+// dotted-path lookup in ctx (or a literal), optionally piped through
+// one or more registered filters, e.g. `user.name | upper` or
+// `items | join(", ")`.
+func evalExpr(expr string, ctx map[string]any) (any, error) {
+	stages := strings.Split(expr, "|")
+
+	value, err := resolvePath(strings.TrimSpace(stages[0]), ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stage := range stages[1:] {
+		name, args, err := parseFilterCall(strings.TrimSpace(stage))
+		if err != nil {
+			return nil, err
+		}
+
+		fn, ok := filters[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown filter %q", name)
+		}
+
+		value, err = fn(value, args...)
+		if err != nil {
+			return nil, fmt.Errorf("filter %q: %w", name, err)
+		}
+	}
+
+	return value, nil
+}
+
+// resolvePath resolves a dotted identifier path (e.g. "user.name")
+// against ctx, or parses expr as a literal (string, number, bool) if it
+// isn't a variable reference.
+func resolvePath(expr string, ctx map[string]any) (any, error) {
+	if expr == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	if lit, ok := parseLiteral(expr); ok {
+		return lit, nil
+	}
+
+	var current any = ctx
+	for _, part := range strings.Split(expr, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot resolve %q: %q is not an object", expr, part)
+		}
+		value, ok := m[part]
+		if !ok {
+			return nil, fmt.Errorf("undefined variable %q", expr)
+		}
+		current = value
+	}
+	return current, nil
+}
+
+func parseLiteral(expr string) (any, bool) {
+	if len(expr) >= 2 {
+		if (strings.HasPrefix(expr, `"`) && strings.HasSuffix(expr, `"`)) ||
+			(strings.HasPrefix(expr, `'`) && strings.HasSuffix(expr, `'`)) {
+			return expr[1 : len(expr)-1], true
+		}
+	}
+	if i, err := strconv.Atoi(expr); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(expr, 64); err == nil {
+		return f, true
+	}
+	if expr == "true" || expr == "false" {
+		return expr == "true", true
+	}
+	return nil, false
+}
+
+// parseFilterCall splits "name" or "name(arg1, arg2)" into the filter
+// name and its literal arguments.
+func parseFilterCall(stage string) (name string, args []any, err error) {
+	open := strings.Index(stage, "(")
+	if open == -1 {
+		return stage, nil, nil
+	}
+	if !strings.HasSuffix(stage, ")") {
+		return "", nil, fmt.Errorf("malformed filter call %q", stage)
+	}
+
+	name = strings.TrimSpace(stage[:open])
+	argList := strings.TrimSpace(stage[open+1 : len(stage)-1])
+	if argList == "" {
+		return name, nil, nil
+	}
+
+	// Split on commas outside quoted strings, e.g. `join(", ")`.
+	var raws []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(argList); i++ {
+		if c := argList[i]; quote != 0 && c == quote {
+			quote = 0
+		} else if quote == 0 && (c == '"' || c == '\'') {
+			quote = c
+		} else if quote == 0 && c == ',' {
+			raws = append(raws, argList[start:i])
+			start = i + 1
+		}
+	}
+	raws = append(raws, argList[start:])
+	for _, raw := range raws {
+		lit, ok := parseLiteral(strings.TrimSpace(raw))
+		if !ok {
+			return "", nil, fmt.Errorf("filter %q: unsupported argument %q", name, raw)
+		}
+		args = append(args, lit)
+	}
+	return name, args, nil
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	case int:
+		return v != 0
+	case float64:
+		return v != 0
+	default:
+		return true
+	}
+}
+
+func toSlice(value any) ([]any, error) {
+	switch v := value.(type) {
+	case []any:
+		return v, nil
+	case []string:
+		items := make([]any, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("%v is not iterable", value)
+	}
+}
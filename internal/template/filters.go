@@ -0,0 +1,71 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// FilterFunc transforms a piped value, e.g. `{{ name | upper }}`. Extra
+// arguments come from a parenthesized filter call like
+// `{{ items | join(", ") }}`.
+type FilterFunc func(value any, args ...any) (any, error)
+
+var filters = map[string]FilterFunc{
+	"upper": filterUpper,
+	"date":  filterDate,
+	"join":  filterJoin,
+}
+
+// RegisterFilter adds or replaces a named filter that `{{ expr | name }}`
+// can invoke. Built-in filters (upper, date, join) can be overridden the
+// same way.
+func RegisterFilter(name string, fn FilterFunc) {
+	filters[name] = fn
+}
+
+func filterUpper(value any, _ ...any) (any, error) {
+	return strings.ToUpper(fmt.Sprint(value)), nil
+}
+
+func filterDate(value any, args ...any) (any, error) {
+	layout := "2006-01-02"
+	if len(args) > 0 {
+		if s, ok := args[0].(string); ok {
+			layout = s
+		}
+	}
+
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(layout), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("date: %w", err)
+		}
+		return t.Format(layout), nil
+	default:
+		return nil, fmt.Errorf("date: unsupported value %v", value)
+	}
+}
+
+func filterJoin(value any, args ...any) (any, error) {
+	sep := ","
+	if len(args) > 0 {
+		if s, ok := args[0].(string); ok {
+			sep = s
+		}
+	}
+
+	items, err := toSlice(value)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = fmt.Sprint(item)
+	}
+	return strings.Join(parts, sep), nil
+}
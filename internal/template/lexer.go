@@ -0,0 +1,89 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// segmentKind identifies the kind of a raw template segment produced by
+// the lexer, before it is turned into an AST node.
+type segmentKind int
+
+const (
+	segText segmentKind = iota
+	segExpr
+	segTag
+)
+
+// segment is one lexed chunk of a template: literal text, or the
+// trimmed content between `{{ }}` or `{% %}` delimiters.
+type segment struct {
+	kind segmentKind
+	text string
+}
+
+// lex splits src into a flat stream of text/expr/tag segments.
+func lex(src string) ([]segment, error) {
+	var segments []segment
+	rest := src
+
+	for {
+		idx, isTag := nextDelim(rest)
+		if idx == -1 {
+			if rest != "" {
+				segments = append(segments, segment{kind: segText, text: rest})
+			}
+			return segments, nil
+		}
+
+		if rest[:idx] != "" {
+			segments = append(segments, segment{kind: segText, text: rest[:idx]})
+		}
+
+		closeDelim, kind := "}}", segExpr
+		if isTag {
+			closeDelim, kind = "%}", segTag
+		}
+
+		closeIdx := strings.Index(rest[idx:], closeDelim)
+		if closeIdx == -1 {
+			return nil, fmt.Errorf("unterminated %q", rest[idx:idx+2])
+		}
+		closeIdx += idx
+
+		content := strings.TrimSpace(rest[idx+2 : closeIdx])
+		segments = append(segments, segment{kind: kind, text: content})
+		rest = rest[closeIdx+len(closeDelim):]
+	}
+}
+
+// nextDelim returns the index of the next "{{" or "{%" in rest, and
+// whether it was a tag delimiter, or -1 if neither appears.
+func nextDelim(rest string) (int, bool) {
+	exprIdx := strings.Index(rest, "{{")
+	tagIdx := strings.Index(rest, "{%")
+
+	switch {
+	case exprIdx == -1 && tagIdx == -1:
+		return -1, false
+	case exprIdx == -1:
+		return tagIdx, true
+	case tagIdx == -1:
+		return exprIdx, false
+	case tagIdx < exprIdx:
+		return tagIdx, true
+	default:
+		return exprIdx, false
+	}
+}
+
+// splitTag splits a tag's content into its keyword and the remainder,
+// e.g. "for x in xs" -> ("for", "x in xs").
+func splitTag(content string) (tag string, rest string) {
+	tag = content
+	if idx := strings.IndexAny(content, " \t"); idx != -1 {
+		tag = content[:idx]
+		rest = strings.TrimSpace(content[idx+1:])
+	}
+	return tag, rest
+}
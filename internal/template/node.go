@@ -0,0 +1,113 @@
+package template
+
+import (
+	"fmt"
+	"html"
+	"io"
+)
+
+// Node is a piece of a parsed template: literal text, a `{{ expr }}`
+// interpolation, or a `{% %}` block tag with nested child nodes.
+type Node interface {
+	Render(w io.Writer, ctx map[string]any) error
+}
+
+type textNode struct{ text string }
+
+func (n *textNode) Render(w io.Writer, _ map[string]any) error {
+	_, err := io.WriteString(w, n.text)
+	return err
+}
+
+type exprNode struct{ expr string }
+
+// Render interpolates the expression's value HTML-escaped, since
+// rendered output is served to browsers as-is by `nexus serve` and
+// `--render`; there's no `|safe`-style opt-out yet, so every value is
+// treated as untrusted.
+func (n *exprNode) Render(w io.Writer, ctx map[string]any) error {
+	value, err := evalExpr(n.expr, ctx)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, html.EscapeString(fmt.Sprint(value)))
+	return err
+}
+
+// ifNode implements `{% if cond %}...{% else %}...{% endif %}`.
+type ifNode struct {
+	cond string
+	then []Node
+	els  []Node
+}
+
+func (n *ifNode) Render(w io.Writer, ctx map[string]any) error {
+	value, err := evalExpr(n.cond, ctx)
+	if err != nil {
+		return err
+	}
+
+	branch := n.els
+	if truthy(value) {
+		branch = n.then
+	}
+
+	for _, child := range branch {
+		if err := child.Render(w, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forNode implements `{% for x in xs %}...{% endfor %}`.
+type forNode struct {
+	varName string
+	iter    string
+	body    []Node
+}
+
+func (n *forNode) Render(w io.Writer, ctx map[string]any) error {
+	value, err := evalExpr(n.iter, ctx)
+	if err != nil {
+		return err
+	}
+
+	items, err := toSlice(value)
+	if err != nil {
+		return fmt.Errorf("for %s in %s: %w", n.varName, n.iter, err)
+	}
+
+	for _, item := range items {
+		loopCtx := make(map[string]any, len(ctx)+1)
+		for k, v := range ctx {
+			loopCtx[k] = v
+		}
+		loopCtx[n.varName] = item
+
+		for _, child := range n.body {
+			if err := child.Render(w, loopCtx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// blockNode implements `{% block name %}...{% endblock %}`. A block
+// renders its body in place; its name is kept on the node, but blocks
+// cannot be overridden by name (there is no template inheritance) and
+// Template offers no lookup of a block by name.
+type blockNode struct {
+	name string
+	body []Node
+}
+
+func (n *blockNode) Render(w io.Writer, ctx map[string]any) error {
+	for _, child := range n.body {
+		if err := child.Render(w, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+)
+
+// blockStopTags are the tag keywords that end the node list currently
+// being collected by parseBlock, handed back to the caller so it can
+// tell an `{% else %}` from an `{% endif %}` etc.
+var blockStopTags = map[string]bool{
+	"else":     true,
+	"endif":    true,
+	"endfor":   true,
+	"endblock": true,
+}
+
+// parseBlock consumes segments starting at i, building nodes until it
+// hits end-of-input or a tag in blockStopTags, which it reports via
+// stopTag without consuming past it.
+func parseBlock(segments []segment, i int) (nodes []Node, next int, stopTag string, err error) {
+	for i < len(segments) {
+		seg := segments[i]
+
+		switch seg.kind {
+		case segText:
+			nodes = append(nodes, &textNode{text: seg.text})
+			i++
+
+		case segExpr:
+			nodes = append(nodes, &exprNode{expr: seg.text})
+			i++
+
+		case segTag:
+			tag, rest := splitTag(seg.text)
+			if blockStopTags[tag] {
+				return nodes, i + 1, tag, nil
+			}
+
+			var node Node
+			var consumed int
+			switch tag {
+			case "if":
+				node, consumed, err = parseIf(segments, i, rest)
+			case "for":
+				node, consumed, err = parseFor(segments, i, rest)
+			case "block":
+				node, consumed, err = parseBlockTag(segments, i, rest)
+			default:
+				err = fmt.Errorf("unknown template tag %q", tag)
+			}
+			if err != nil {
+				return nil, 0, "", err
+			}
+
+			nodes = append(nodes, node)
+			i = consumed
+		}
+	}
+
+	return nodes, i, "", nil
+}
+
+func parseIf(segments []segment, i int, cond string) (Node, int, error) {
+	cond = strings.TrimSpace(cond)
+	if cond == "" {
+		return nil, 0, fmt.Errorf("if tag missing condition")
+	}
+
+	thenNodes, next, stop, err := parseBlock(segments, i+1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	node := &ifNode{cond: cond, then: thenNodes}
+
+	if stop == "else" {
+		elseNodes, next2, stop2, err := parseBlock(segments, next)
+		if err != nil {
+			return nil, 0, err
+		}
+		if stop2 != "endif" {
+			return nil, 0, fmt.Errorf("if: expected endif, got %q", stop2)
+		}
+		node.els = elseNodes
+		return node, next2, nil
+	}
+
+	if stop != "endif" {
+		return nil, 0, fmt.Errorf("if: expected endif, got %q", stop)
+	}
+
+	return node, next, nil
+}
+
+func parseFor(segments []segment, i int, header string) (Node, int, error) {
+	parts := strings.Fields(header)
+	if len(parts) != 3 || parts[1] != "in" {
+		return nil, 0, fmt.Errorf("malformed for tag: expected 'for x in xs', got %q", header)
+	}
+
+	body, next, stop, err := parseBlock(segments, i+1)
+	if err != nil {
+		return nil, 0, err
+	}
+	if stop != "endfor" {
+		return nil, 0, fmt.Errorf("for: expected endfor, got %q", stop)
+	}
+
+	return &forNode{varName: parts[0], iter: parts[2], body: body}, next, nil
+}
+
+func parseBlockTag(segments []segment, i int, name string) (Node, int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, 0, fmt.Errorf("block tag missing name")
+	}
+
+	body, next, stop, err := parseBlock(segments, i+1)
+	if err != nil {
+		return nil, 0, err
+	}
+	if stop != "endblock" {
+		return nil, 0, fmt.Errorf("block %q: expected endblock, got %q", name, stop)
+	}
+
+	return &blockNode{name: name, body: body}, next, nil
+}
@@ -0,0 +1,101 @@
+// Package template parses the lines captured from a Nexus `template {
+// ... }` block into an AST of text nodes, `{{ expr }}` interpolations,
+// and `{% %}` block tags (if/for/block), in the style of pongo2/Jinja,
+// and renders that AST to HTML given a context.
+package template
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"nexus/internal/parser"
+)
+
+// Template is a parsed template ready to be rendered with a context.
+type Template struct {
+	nodes []Node
+}
+
+// Parse parses the captured lines of a `template { ... }` block.
+func Parse(lines []string) (*Template, error) {
+	src := strings.Join(lines, "\n")
+
+	segments, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, _, stop, err := parseBlock(segments, 0)
+	if err != nil {
+		return nil, err
+	}
+	if stop != "" {
+		return nil, fmt.Errorf("%q tag has no matching opener", stop)
+	}
+
+	return &Template{nodes: nodes}, nil
+}
+
+// Execute renders the template to w using ctx to resolve variables.
+func (t *Template) Execute(w io.Writer, ctx map[string]any) error {
+	for _, node := range t.nodes {
+		if err := node.Render(w, ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Render renders the template and returns the result as a string.
+func (t *Template) Render(ctx map[string]any) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, ctx); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// NewContext builds a rendering context from a module's state
+// properties and action names, applying overrides on top of the
+// module's declared initial values.
+func NewContext(module *parser.Module, overrides map[string]any) map[string]any {
+	ctx := make(map[string]any, len(module.State)+1)
+
+	for _, prop := range module.State {
+		ctx[prop.Name] = coerceValue(prop)
+	}
+
+	actionNames := make([]string, len(module.Actions))
+	for i, action := range module.Actions {
+		actionNames[i] = action.Name
+	}
+	ctx["actions"] = actionNames
+
+	for key, value := range overrides {
+		ctx[key] = value
+	}
+
+	return ctx
+}
+
+// coerceValue converts a state property's string value to the Go type
+// implied by its declared type, falling back to the raw string.
+func coerceValue(prop parser.Property) any {
+	switch prop.Type {
+	case "int":
+		if i, err := strconv.Atoi(prop.Value); err == nil {
+			return i
+		}
+	case "float":
+		if f, err := strconv.ParseFloat(prop.Value, 64); err == nil {
+			return f
+		}
+	case "bool":
+		if b, err := strconv.ParseBool(prop.Value); err == nil {
+			return b
+		}
+	}
+	return prop.Value
+}
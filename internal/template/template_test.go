@@ -0,0 +1,127 @@
+package template
+
+import (
+	"strings"
+	"testing"
+
+	"nexus/internal/parser"
+)
+
+func TestTemplateRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		ctx     map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "text and interpolation",
+			lines: []string{"<h1>Hello, {{ name }}</h1>"},
+			ctx:   map[string]any{"name": "Ada"},
+			want:  "<h1>Hello, Ada</h1>",
+		},
+		{
+			name:  "filter pipeline",
+			lines: []string{"{{ name | upper }}"},
+			ctx:   map[string]any{"name": "ada"},
+			want:  "ADA",
+		},
+		{
+			name:  "if/else",
+			lines: []string{"{% if loggedIn %}Welcome{% else %}Please log in{% endif %}"},
+			ctx:   map[string]any{"loggedIn": false},
+			want:  "Please log in",
+		},
+		{
+			name:  "for loop",
+			lines: []string{"{% for item in items %}[{{ item }}]{% endfor %}"},
+			ctx:   map[string]any{"items": []any{"a", "b"}},
+			want:  "[a][b]",
+		},
+		{
+			name:  "interpolation is html-escaped",
+			lines: []string{"<p>{{ bio }}</p>"},
+			ctx:   map[string]any{"bio": "<script>alert(1)</script>"},
+			want:  "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+		},
+		{
+			name:    "unknown filter",
+			lines:   []string{"{{ name | shout }}"},
+			ctx:     map[string]any{"name": "ada"},
+			wantErr: true,
+		},
+		{
+			name:    "unclosed if",
+			lines:   []string{"{% if loggedIn %}Welcome"},
+			ctx:     map[string]any{"loggedIn": true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.lines)
+			if err != nil {
+				if tt.wantErr {
+					return
+				}
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got, err := tmpl.Render(tt.ctx)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Render() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterFilter(t *testing.T) {
+	RegisterFilter("shout", func(value any, _ ...any) (any, error) {
+		return strings.ToUpper(value.(string)) + "!", nil
+	})
+
+	tmpl, err := Parse([]string{"{{ name | shout }}"})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got, err := tmpl.Render(map[string]any{"name": "ada"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if want := "ADA!"; got != want {
+		t.Errorf("Render() = %q; want %q", got, want)
+	}
+}
+
+func TestNewContext(t *testing.T) {
+	module := &parser.Module{
+		Name: "App",
+		State: []parser.Property{
+			{Name: "count", Type: "int", Value: "3"},
+		},
+		Actions: []parser.Action{{Name: "login"}},
+	}
+
+	ctx := NewContext(module, map[string]any{"count": 5})
+
+	if ctx["count"] != 5 {
+		t.Errorf("count = %v; want override 5", ctx["count"])
+	}
+
+	actions, ok := ctx["actions"].([]string)
+	if !ok || len(actions) != 1 || actions[0] != "login" {
+		t.Errorf("actions = %v; want [login]", ctx["actions"])
+	}
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"nexus/internal/devserver"
 	"nexus/internal/parser"
+	"nexus/internal/template"
 )
 
 const (
@@ -26,6 +30,7 @@ const (
 type Config struct {
 	Filename string
 	Verbose  bool
+	Render   bool
 }
 
 // Application represents the main application state
@@ -34,6 +39,14 @@ type Application struct {
 }
 
 func main() {
+	if len(os.Args) >= 2 && os.Args[1] == "serve" {
+		if err := runServe(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(ExitInvalidArgs)
+		}
+		return
+	}
+
 	app := &Application{}
 
 	if err := app.parseArgs(); err != nil {
@@ -48,13 +61,86 @@ func main() {
 	}
 }
 
+// runServe handles the `nexus serve <file.nx>` subcommand: it starts an
+// HTTP server rendering the module's template, optionally watching the
+// source file and pushing live-reload events to connected browsers.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":4000", "address to listen on")
+	watch := fs.Bool("watch", false, "watch the source file and live-reload connected browsers on change")
+
+	// flag.FlagSet.Parse stops at the first non-flag argument, so the
+	// filename would have to come last for --addr/--watch to be seen at
+	// all. Pull the filename out ourselves first so flags can appear in
+	// any position relative to it.
+	filename, flagArgs := splitServeArgs(args)
+	if err := fs.Parse(flagArgs); err != nil {
+		return err
+	}
+
+	if filename == "" {
+		return fmt.Errorf("missing required filename argument")
+	}
+
+	if !strings.HasSuffix(filename, FileExtension) {
+		return fmt.Errorf("invalid file extension: expected %s, got %s",
+			FileExtension, filepath.Ext(filename))
+	}
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return fmt.Errorf("file does not exist: %s", filename)
+	}
+
+	return devserver.New(filename, *addr, *watch).Run()
+}
+
+// splitServeArgs separates the `serve` subcommand's arguments into its
+// positional filename and the remaining flag arguments, so callers can
+// feed the latter to a flag.FlagSet without losing flags that come
+// after the filename.
+func splitServeArgs(args []string) (filename string, flagArgs []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--addr" || arg == "-addr":
+			flagArgs = append(flagArgs, arg)
+			if i+1 < len(args) {
+				i++
+				flagArgs = append(flagArgs, args[i])
+			}
+		case arg == "--watch" || arg == "-watch":
+			flagArgs = append(flagArgs, arg)
+		case strings.HasPrefix(arg, "--addr=") || strings.HasPrefix(arg, "-addr="):
+			flagArgs = append(flagArgs, arg)
+		default:
+			if filename == "" {
+				filename = arg
+			}
+		}
+	}
+	return filename, flagArgs
+}
+
 // parseArgs parses and validates command line arguments
 func (app *Application) parseArgs() error {
 	if len(os.Args) < 2 {
 		return fmt.Errorf("missing required filename argument")
 	}
 
-	filename := os.Args[1]
+	var filename string
+	for _, arg := range os.Args[1:] {
+		switch arg {
+		case "--render":
+			app.config.Render = true
+		default:
+			if filename == "" {
+				filename = arg
+			}
+		}
+	}
+
+	if filename == "" {
+		return fmt.Errorf("missing required filename argument")
+	}
 
 	// Validate file extension
 	if !strings.HasSuffix(filename, FileExtension) {
@@ -95,7 +181,11 @@ func (app *Application) displayModuleSummary(module *parser.Module) {
 	app.displayStateVariables(module.State)
 
 	// Template section
-	app.displayTemplate(module.Template)
+	if app.config.Render {
+		app.displayRenderedTemplate(module)
+	} else {
+		app.displayTemplate(module.Template)
+	}
 
 	// Actions section
 	app.displayActions(module.Actions)
@@ -138,6 +228,34 @@ func (app *Application) displayTemplate(template []string) {
 	fmt.Println()
 }
 
+// displayRenderedTemplate parses the module's template and renders it to
+// HTML using the module's initial state values, in place of the raw
+// source dump shown by displayTemplate. Requires --render.
+func (app *Application) displayRenderedTemplate(module *parser.Module) {
+	fmt.Println("Rendered Template:")
+	if len(module.Template) == 0 {
+		fmt.Println("  No template defined")
+		fmt.Println()
+		return
+	}
+
+	tmpl, err := template.Parse(module.Template)
+	if err != nil {
+		fmt.Printf("  Failed to parse template: %v\n\n", err)
+		return
+	}
+
+	ctx := template.NewContext(module, nil)
+	output, err := tmpl.Render(ctx)
+	if err != nil {
+		fmt.Printf("  Failed to render template: %v\n\n", err)
+		return
+	}
+
+	fmt.Println(output)
+	fmt.Println()
+}
+
 // displayActions formats and displays available actions
 func (app *Application) displayActions(actions []parser.Action) {
 	fmt.Println("Actions:")
@@ -149,22 +267,66 @@ func (app *Application) displayActions(actions []parser.Action) {
 
 	fmt.Printf("  %d action(s) available:\n", len(actions))
 	for i, action := range actions {
-		fmt.Printf("  [%d] %s\n", i+1, action.Name)
+		fmt.Printf("  [%d] %s%s\n", i+1, action.Name, formatActionSignature(action))
 	}
 	fmt.Println()
 }
 
-// printError outputs formatted error messages
+// formatActionSignature renders an action's parameter list and return
+// type, e.g. "(username: string, count: int = 0) -> bool", or an empty
+// string for the legacy parameterless form.
+func formatActionSignature(action parser.Action) string {
+	if len(action.Parameters) == 0 && action.ReturnType == "" {
+		return ""
+	}
+
+	params := make([]string, len(action.Parameters))
+	for i, param := range action.Parameters {
+		params[i] = fmt.Sprintf("%s: %s", param.Name, param.Type)
+		if param.Default != "" {
+			params[i] += fmt.Sprintf(" = %s", param.Default)
+		}
+	}
+
+	signature := fmt.Sprintf("(%s)", strings.Join(params, ", "))
+	if action.ReturnType != "" {
+		signature += fmt.Sprintf(" -> %s", action.ReturnType)
+	}
+	return signature
+}
+
+// printError outputs formatted error messages. Parse errors are expanded
+// into a source-context snippet per diagnostic (via parser.RenderError)
+// so users see every problem in the file at once instead of just the
+// first one.
 func (app *Application) printError(context string, err error) {
+	var list *parser.ErrorList
+	var pe *parser.ParseError
+	if errors.As(err, &list) || errors.As(err, &pe) {
+		fmt.Fprintf(os.Stderr, "Error: %s\n\n", context)
+		parser.RenderError(err, os.Stderr)
+		return
+	}
+
 	fmt.Fprintf(os.Stderr, "Error: %s - %v\n", context, err)
 }
 
 // printUsage displays usage information
 func (app *Application) printUsage() {
-	fmt.Printf("Usage: %s <filename%s>\n", strings.ToLower(AppName), FileExtension)
+	fmt.Printf("Usage: %s <filename%s> [--render]\n", strings.ToLower(AppName), FileExtension)
+	fmt.Printf("       %s serve <filename%s> [--addr :4000] [--watch]\n", strings.ToLower(AppName), FileExtension)
 	fmt.Printf("Version: %s\n", Version)
 	fmt.Println()
+	fmt.Println("Flags:")
+	fmt.Println("  --render   execute the module's template with its initial state and print the result")
+	fmt.Println()
+	fmt.Println("Subcommands:")
+	fmt.Println("  serve      start a dev server rendering the module's template over HTTP")
+	fmt.Println("    --addr   address to listen on (default :4000)")
+	fmt.Println("    --watch  live-reload connected browsers when the source file changes")
+	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Printf("  %s app.nx\n", strings.ToLower(AppName))
 	fmt.Printf("  %s components/header.nx\n", strings.ToLower(AppName))
+	fmt.Printf("  %s serve app.nx --watch\n", strings.ToLower(AppName))
 }